@@ -0,0 +1,595 @@
+package function
+
+import (
+	"encoding/json"
+	"fmt"
+
+	enry "gopkg.in/src-d/enry.v1"
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+)
+
+// unaryPathFunc is the shared implementation backing the single-argument,
+// boolean-returning enry classifiers below (IsVendor, IsDocumentation,
+// IsConfiguration, IsImage). It is not exported: each UDF still gets its
+// own named type, matching the shape used throughout this package, but the
+// Expression plumbing that only differs by name and predicate is factored
+// out to avoid repeating it four times over.
+type unaryPathFunc struct {
+	Left sql.Expression
+	name string
+	fn   func(path string) bool
+}
+
+func newUnaryPathFunc(name string, fn func(path string) bool, args ...sql.Expression) (*unaryPathFunc, error) {
+	if len(args) != 1 {
+		return nil, sql.ErrInvalidArgumentNumber.New("1", len(args))
+	}
+	return &unaryPathFunc{Left: args[0], name: name, fn: fn}, nil
+}
+
+// Resolved implements the Expression interface.
+func (f *unaryPathFunc) Resolved() bool { return f.Left.Resolved() }
+
+func (f *unaryPathFunc) String() string { return fmt.Sprintf("%s(%s)", f.name, f.Left) }
+
+// IsNullable implements the Expression interface.
+func (f *unaryPathFunc) IsNullable() bool { return f.Left.IsNullable() }
+
+// Type implements the Expression interface.
+func (*unaryPathFunc) Type() sql.Type { return sql.Boolean }
+
+// Children implements the Expression interface.
+func (f *unaryPathFunc) Children() []sql.Expression { return []sql.Expression{f.Left} }
+
+// Eval implements the Expression interface.
+func (f *unaryPathFunc) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	left, err := f.Left.Eval(ctx, row)
+	if err != nil {
+		return nil, err
+	}
+
+	if left == nil {
+		return nil, nil
+	}
+
+	left, err = sql.Text.Convert(left)
+	if err != nil {
+		return nil, err
+	}
+
+	return f.fn(left.(string)), nil
+}
+
+// IsVendor reports whether a path looks like vendored, third-party code.
+type IsVendor struct{ *unaryPathFunc }
+
+// NewIsVendor creates a new IsVendor UDF.
+func NewIsVendor(args ...sql.Expression) (sql.Expression, error) {
+	f, err := newUnaryPathFunc("is_vendor", enry.IsVendor, args...)
+	if err != nil {
+		return nil, err
+	}
+	return &IsVendor{f}, nil
+}
+
+// TransformUp implements the Expression interface.
+func (f *IsVendor) TransformUp(fn sql.TransformExprFunc) (sql.Expression, error) {
+	left, err := f.Left.TransformUp(fn)
+	if err != nil {
+		return nil, err
+	}
+	return fn(&IsVendor{&unaryPathFunc{Left: left, name: f.name, fn: f.fn}})
+}
+
+// IsDocumentation reports whether a path looks like documentation.
+type IsDocumentation struct{ *unaryPathFunc }
+
+// NewIsDocumentation creates a new IsDocumentation UDF.
+func NewIsDocumentation(args ...sql.Expression) (sql.Expression, error) {
+	f, err := newUnaryPathFunc("is_documentation", enry.IsDocumentation, args...)
+	if err != nil {
+		return nil, err
+	}
+	return &IsDocumentation{f}, nil
+}
+
+// TransformUp implements the Expression interface.
+func (f *IsDocumentation) TransformUp(fn sql.TransformExprFunc) (sql.Expression, error) {
+	left, err := f.Left.TransformUp(fn)
+	if err != nil {
+		return nil, err
+	}
+	return fn(&IsDocumentation{&unaryPathFunc{Left: left, name: f.name, fn: f.fn}})
+}
+
+// IsConfiguration reports whether a path looks like a configuration file.
+type IsConfiguration struct{ *unaryPathFunc }
+
+// NewIsConfiguration creates a new IsConfiguration UDF.
+func NewIsConfiguration(args ...sql.Expression) (sql.Expression, error) {
+	f, err := newUnaryPathFunc("is_configuration", enry.IsConfiguration, args...)
+	if err != nil {
+		return nil, err
+	}
+	return &IsConfiguration{f}, nil
+}
+
+// TransformUp implements the Expression interface.
+func (f *IsConfiguration) TransformUp(fn sql.TransformExprFunc) (sql.Expression, error) {
+	left, err := f.Left.TransformUp(fn)
+	if err != nil {
+		return nil, err
+	}
+	return fn(&IsConfiguration{&unaryPathFunc{Left: left, name: f.name, fn: f.fn}})
+}
+
+// IsImage reports whether a path looks like an image file.
+type IsImage struct{ *unaryPathFunc }
+
+// NewIsImage creates a new IsImage UDF.
+func NewIsImage(args ...sql.Expression) (sql.Expression, error) {
+	f, err := newUnaryPathFunc("is_image", enry.IsImage, args...)
+	if err != nil {
+		return nil, err
+	}
+	return &IsImage{f}, nil
+}
+
+// TransformUp implements the Expression interface.
+func (f *IsImage) TransformUp(fn sql.TransformExprFunc) (sql.Expression, error) {
+	left, err := f.Left.TransformUp(fn)
+	if err != nil {
+		return nil, err
+	}
+	return fn(&IsImage{&unaryPathFunc{Left: left, name: f.name, fn: f.fn}})
+}
+
+// IsBinary reports whether a blob's content looks like binary data.
+type IsBinary struct {
+	Left sql.Expression
+}
+
+// NewIsBinary creates a new IsBinary UDF.
+func NewIsBinary(args ...sql.Expression) (sql.Expression, error) {
+	if len(args) != 1 {
+		return nil, sql.ErrInvalidArgumentNumber.New("1", len(args))
+	}
+	return &IsBinary{args[0]}, nil
+}
+
+// Resolved implements the Expression interface.
+func (f *IsBinary) Resolved() bool { return f.Left.Resolved() }
+
+func (f *IsBinary) String() string { return fmt.Sprintf("is_binary(%s)", f.Left) }
+
+// IsNullable implements the Expression interface.
+func (f *IsBinary) IsNullable() bool { return f.Left.IsNullable() }
+
+// Type implements the Expression interface.
+func (*IsBinary) Type() sql.Type { return sql.Boolean }
+
+// TransformUp implements the Expression interface.
+func (f *IsBinary) TransformUp(fn sql.TransformExprFunc) (sql.Expression, error) {
+	left, err := f.Left.TransformUp(fn)
+	if err != nil {
+		return nil, err
+	}
+	return fn(&IsBinary{left})
+}
+
+// Eval implements the Expression interface.
+func (f *IsBinary) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	left, err := f.Left.Eval(ctx, row)
+	if err != nil {
+		return nil, err
+	}
+
+	if left == nil {
+		return nil, nil
+	}
+
+	left, err = sql.Blob.Convert(left)
+	if err != nil {
+		return nil, err
+	}
+
+	return enry.IsBinary(left.([]byte)), nil
+}
+
+// Children implements the Expression interface.
+func (f *IsBinary) Children() []sql.Expression { return []sql.Expression{f.Left} }
+
+// IsGenerated reports whether a file, given its path and content, looks
+// like it was generated rather than hand-written.
+type IsGenerated struct {
+	Left  sql.Expression
+	Right sql.Expression
+}
+
+// NewIsGenerated creates a new IsGenerated UDF.
+func NewIsGenerated(args ...sql.Expression) (sql.Expression, error) {
+	if len(args) != 2 {
+		return nil, sql.ErrInvalidArgumentNumber.New("2", len(args))
+	}
+	return &IsGenerated{args[0], args[1]}, nil
+}
+
+// Resolved implements the Expression interface.
+func (f *IsGenerated) Resolved() bool {
+	return f.Left.Resolved() && f.Right.Resolved()
+}
+
+func (f *IsGenerated) String() string {
+	return fmt.Sprintf("is_generated(%s, %s)", f.Left, f.Right)
+}
+
+// IsNullable implements the Expression interface.
+func (f *IsGenerated) IsNullable() bool {
+	return f.Left.IsNullable() || f.Right.IsNullable()
+}
+
+// Type implements the Expression interface.
+func (*IsGenerated) Type() sql.Type { return sql.Boolean }
+
+// TransformUp implements the Expression interface.
+func (f *IsGenerated) TransformUp(fn sql.TransformExprFunc) (sql.Expression, error) {
+	left, err := f.Left.TransformUp(fn)
+	if err != nil {
+		return nil, err
+	}
+
+	right, err := f.Right.TransformUp(fn)
+	if err != nil {
+		return nil, err
+	}
+
+	return fn(&IsGenerated{left, right})
+}
+
+// Eval implements the Expression interface.
+func (f *IsGenerated) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	left, err := f.Left.Eval(ctx, row)
+	if err != nil {
+		return nil, err
+	}
+
+	if left == nil {
+		return nil, nil
+	}
+
+	left, err = sql.Text.Convert(left)
+	if err != nil {
+		return nil, err
+	}
+
+	right, err := f.Right.Eval(ctx, row)
+	if err != nil {
+		return nil, err
+	}
+
+	if right == nil {
+		return nil, nil
+	}
+
+	right, err = sql.Blob.Convert(right)
+	if err != nil {
+		return nil, err
+	}
+
+	return enry.IsGenerated(left.(string), right.([]byte)), nil
+}
+
+// Children implements the Expression interface.
+func (f *IsGenerated) Children() []sql.Expression {
+	return []sql.Expression{f.Left, f.Right}
+}
+
+// languageByFunc is the shared implementation backing the single-argument,
+// nullable-text enry language lookups below (LanguageByExtension,
+// LanguageByFilename), which only differ in which enry function they call
+// and whether it reports a "safe" match.
+type languageByFunc struct {
+	Left sql.Expression
+	name string
+	fn   func(path string) (string, bool)
+}
+
+func newLanguageByFunc(name string, fn func(path string) (string, bool), args ...sql.Expression) (*languageByFunc, error) {
+	if len(args) != 1 {
+		return nil, sql.ErrInvalidArgumentNumber.New("1", len(args))
+	}
+	return &languageByFunc{Left: args[0], name: name, fn: fn}, nil
+}
+
+// Resolved implements the Expression interface.
+func (f *languageByFunc) Resolved() bool { return f.Left.Resolved() }
+
+func (f *languageByFunc) String() string { return fmt.Sprintf("%s(%s)", f.name, f.Left) }
+
+// IsNullable implements the Expression interface.
+func (f *languageByFunc) IsNullable() bool { return true }
+
+// Type implements the Expression interface.
+func (*languageByFunc) Type() sql.Type { return sql.Text }
+
+// Children implements the Expression interface.
+func (f *languageByFunc) Children() []sql.Expression { return []sql.Expression{f.Left} }
+
+// Eval implements the Expression interface.
+func (f *languageByFunc) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	left, err := f.Left.Eval(ctx, row)
+	if err != nil {
+		return nil, err
+	}
+
+	if left == nil {
+		return nil, nil
+	}
+
+	left, err = sql.Text.Convert(left)
+	if err != nil {
+		return nil, err
+	}
+
+	lang, ok := f.fn(left.(string))
+	if !ok || lang == "" {
+		return nil, nil
+	}
+
+	return lang, nil
+}
+
+// LanguageByExtension guesses a file's language from its path extension
+// alone, without looking at its content.
+type LanguageByExtension struct{ *languageByFunc }
+
+// NewLanguageByExtension creates a new LanguageByExtension UDF.
+func NewLanguageByExtension(args ...sql.Expression) (sql.Expression, error) {
+	f, err := newLanguageByFunc("language_by_extension", enry.GetLanguageByExtension, args...)
+	if err != nil {
+		return nil, err
+	}
+	return &LanguageByExtension{f}, nil
+}
+
+// TransformUp implements the Expression interface.
+func (f *LanguageByExtension) TransformUp(fn sql.TransformExprFunc) (sql.Expression, error) {
+	left, err := f.Left.TransformUp(fn)
+	if err != nil {
+		return nil, err
+	}
+	return fn(&LanguageByExtension{&languageByFunc{Left: left, name: f.name, fn: f.fn}})
+}
+
+// LanguageByFilename guesses a file's language from its bare filename
+// (e.g. "Makefile", "Dockerfile"), without looking at its content.
+type LanguageByFilename struct{ *languageByFunc }
+
+// NewLanguageByFilename creates a new LanguageByFilename UDF.
+func NewLanguageByFilename(args ...sql.Expression) (sql.Expression, error) {
+	f, err := newLanguageByFunc("language_by_filename", enry.GetLanguageByFilename, args...)
+	if err != nil {
+		return nil, err
+	}
+	return &LanguageByFilename{f}, nil
+}
+
+// TransformUp implements the Expression interface.
+func (f *LanguageByFilename) TransformUp(fn sql.TransformExprFunc) (sql.Expression, error) {
+	left, err := f.Left.TransformUp(fn)
+	if err != nil {
+		return nil, err
+	}
+	return fn(&LanguageByFilename{&languageByFunc{Left: left, name: f.name, fn: f.fn}})
+}
+
+// LanguageByContent guesses a file's language from its content, using
+// enry's content heuristics (enry.GetLanguageByContent) rather than the
+// full detection chain used by Language; shebangs and modelines are not
+// consulted. The filename is still passed along, since enry picks the
+// heuristics to run by its extension; it is not a content-only
+// classification.
+type LanguageByContent struct {
+	Left  sql.Expression
+	Right sql.Expression
+}
+
+// NewLanguageByContent creates a new LanguageByContent UDF.
+func NewLanguageByContent(args ...sql.Expression) (sql.Expression, error) {
+	if len(args) != 2 {
+		return nil, sql.ErrInvalidArgumentNumber.New("2", len(args))
+	}
+	return &LanguageByContent{args[0], args[1]}, nil
+}
+
+// Resolved implements the Expression interface.
+func (f *LanguageByContent) Resolved() bool {
+	return f.Left.Resolved() && f.Right.Resolved()
+}
+
+func (f *LanguageByContent) String() string {
+	return fmt.Sprintf("language_by_content(%s, %s)", f.Left, f.Right)
+}
+
+// IsNullable implements the Expression interface.
+func (f *LanguageByContent) IsNullable() bool { return true }
+
+// Type implements the Expression interface.
+func (*LanguageByContent) Type() sql.Type { return sql.Text }
+
+// TransformUp implements the Expression interface.
+func (f *LanguageByContent) TransformUp(fn sql.TransformExprFunc) (sql.Expression, error) {
+	left, err := f.Left.TransformUp(fn)
+	if err != nil {
+		return nil, err
+	}
+
+	right, err := f.Right.TransformUp(fn)
+	if err != nil {
+		return nil, err
+	}
+
+	return fn(&LanguageByContent{left, right})
+}
+
+// Eval implements the Expression interface.
+func (f *LanguageByContent) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	left, err := f.Left.Eval(ctx, row)
+	if err != nil {
+		return nil, err
+	}
+
+	if left == nil {
+		return nil, nil
+	}
+
+	left, err = sql.Text.Convert(left)
+	if err != nil {
+		return nil, err
+	}
+
+	right, err := f.Right.Eval(ctx, row)
+	if err != nil {
+		return nil, err
+	}
+
+	if right == nil {
+		return nil, nil
+	}
+
+	right, err = sql.Blob.Convert(right)
+	if err != nil {
+		return nil, err
+	}
+
+	lang, ok := enry.GetLanguageByContent(left.(string), right.([]byte))
+	if !ok || lang == "" {
+		return nil, nil
+	}
+
+	return lang, nil
+}
+
+// Children implements the Expression interface.
+func (f *LanguageByContent) Children() []sql.Expression {
+	return []sql.Expression{f.Left, f.Right}
+}
+
+// LanguageCandidates returns every language enry considers a plausible
+// match for a file, given its path and content, as a JSON array ranked
+// from most to least likely. Use it instead of Language when a file is
+// ambiguous (e.g. a ".h" header) and callers want to make their own call.
+type LanguageCandidates struct {
+	Left  sql.Expression
+	Right sql.Expression
+}
+
+// NewLanguageCandidates creates a new LanguageCandidates UDF.
+func NewLanguageCandidates(args ...sql.Expression) (sql.Expression, error) {
+	var left, right sql.Expression
+	switch len(args) {
+	case 1:
+		left = args[0]
+	case 2:
+		left = args[0]
+		right = args[1]
+	default:
+		return nil, sql.ErrInvalidArgumentNumber.New("1 or 2", len(args))
+	}
+
+	return &LanguageCandidates{left, right}, nil
+}
+
+// Resolved implements the Expression interface.
+func (f *LanguageCandidates) Resolved() bool {
+	return f.Left.Resolved() && (f.Right == nil || f.Right.Resolved())
+}
+
+func (f *LanguageCandidates) String() string {
+	if f.Right == nil {
+		return fmt.Sprintf("language_candidates(%s)", f.Left)
+	}
+	return fmt.Sprintf("language_candidates(%s, %s)", f.Left, f.Right)
+}
+
+// IsNullable implements the Expression interface.
+func (f *LanguageCandidates) IsNullable() bool {
+	return f.Left.IsNullable() || (f.Right != nil && f.Right.IsNullable())
+}
+
+// Type implements the Expression interface.
+func (LanguageCandidates) Type() sql.Type { return sql.JSON }
+
+// TransformUp implements the Expression interface.
+func (f *LanguageCandidates) TransformUp(fn sql.TransformExprFunc) (sql.Expression, error) {
+	left, err := f.Left.TransformUp(fn)
+	if err != nil {
+		return nil, err
+	}
+
+	var right sql.Expression
+	if f.Right != nil {
+		right, err = f.Right.TransformUp(fn)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return fn(&LanguageCandidates{left, right})
+}
+
+// Eval implements the Expression interface.
+func (f *LanguageCandidates) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	left, err := f.Left.Eval(ctx, row)
+	if err != nil {
+		return nil, err
+	}
+
+	if left == nil {
+		return nil, nil
+	}
+
+	left, err = sql.Text.Convert(left)
+	if err != nil {
+		return nil, err
+	}
+
+	path := left.(string)
+	var blob []byte
+
+	if f.Right != nil {
+		right, err := f.Right.Eval(ctx, row)
+		if err != nil {
+			return nil, err
+		}
+
+		if right == nil {
+			return nil, nil
+		}
+
+		right, err = sql.Blob.Convert(right)
+		if err != nil {
+			return nil, err
+		}
+
+		blob = right.([]byte)
+	}
+
+	candidates := enry.GetLanguages(path, blob)
+
+	data, err := json.Marshal(candidates)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// Children implements the Expression interface.
+func (f *LanguageCandidates) Children() []sql.Expression {
+	if f.Right == nil {
+		return []sql.Expression{f.Left}
+	}
+
+	return []sql.Expression{f.Left, f.Right}
+}
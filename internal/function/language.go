@@ -2,6 +2,7 @@ package function
 
 import (
 	"fmt"
+	"time"
 
 	enry "gopkg.in/src-d/enry.v1"
 	"gopkg.in/src-d/go-mysql-server.v0/sql"
@@ -72,9 +73,12 @@ func (f *Language) TransformUp(fn sql.TransformExprFunc) (sql.Expression, error)
 
 // Eval implements the Expression interface.
 func (f *Language) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	logger := loggerForContext(ctx)
 	span, ctx := ctx.Span("gitbase.Language")
 	defer span.Finish()
 
+	start := time.Now()
+
 	left, err := f.Left.Eval(ctx, row)
 	if err != nil {
 		return nil, err
@@ -110,13 +114,30 @@ func (f *Language) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
 		blob = right.([]byte)
 	}
 
-	if lang := enry.GetLanguage(path, blob); lang != "" {
+	logger = logger.With("path", path, "blob_size", len(blob))
+
+	lang := enry.GetLanguage(path, blob)
+	logger.Debug("language detection",
+		"status", statusString(lang != ""),
+		"elapsed", time.Since(start),
+	)
+
+	if lang != "" {
 		return lang, nil
 	}
 
 	return nil, nil
 }
 
+// statusString renders a boolean success flag as the "ok"/"not_found"
+// status attribute used across the function package's structured logs.
+func statusString(ok bool) string {
+	if ok {
+		return "ok"
+	}
+	return "not_found"
+}
+
 // Children implements the Expression interface.
 func (f *Language) Children() []sql.Expression {
 	if f.Right == nil {
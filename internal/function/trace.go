@@ -0,0 +1,42 @@
+package function
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+
+	"gopkg.in/bblfsh/sdk.v2/driver/native"
+	"gopkg.in/src-d/go-mysql-server.v0/sql"
+)
+
+// newTxID returns a short, random transaction id used to tag every log
+// record produced while evaluating a single row/query, so that entries
+// from language detection, the native driver and any other UDF can be
+// correlated after the fact.
+func newTxID() string {
+	var b [4]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+type txLoggerKeyT struct{}
+
+var txLoggerKey txLoggerKeyT
+
+// loggerForContext returns the structured logger for the query ctx
+// belongs to, minting one stamped with a fresh txid the first time it's
+// asked for a given *sql.Context and reusing it for every row evaluated
+// afterwards. The logger is also pushed into ctx via
+// native.ContextWithLogger, so a native.Driver invoked later while
+// evaluating the same query logs under the same txid.
+func loggerForContext(ctx *sql.Context) *slog.Logger {
+	if l, ok := ctx.Value(txLoggerKey).(*slog.Logger); ok && l != nil {
+		return l
+	}
+
+	logger := slog.Default().With("txid", newTxID())
+	base := context.WithValue(ctx.Context, txLoggerKey, logger)
+	ctx.Context = native.ContextWithLogger(base, logger)
+	return logger
+}
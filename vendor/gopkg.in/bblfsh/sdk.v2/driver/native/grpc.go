@@ -0,0 +1,168 @@
+package native
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"gopkg.in/bblfsh/sdk.v2/driver"
+	"gopkg.in/bblfsh/sdk.v2/driver/native/grpcapi"
+	"gopkg.in/bblfsh/sdk.v2/uast/nodes"
+)
+
+// NewGRPCDriver creates a driver.Native that sends Parse calls to a remote
+// native driver over gRPC at addr, instead of spawning a local subprocess
+// speaking stdin/stdout jsonlines. It is a drop-in replacement for
+// NewDriver/NewDriverAt wherever a uast()/language pipeline expects a
+// driver.Native: the caller picks the transport, the rest of the pipeline
+// (including Pool, via NewGRPCPool) doesn't need to know which one it got.
+//
+// Running drivers this way lets a deployment scale and version driver
+// containers independently of the gitbase process using them, instead of
+// bundling a per-language binary into every gitbase replica.
+func NewGRPCDriver(addr string, enc Encoding) driver.Native {
+	if enc == "" {
+		enc = UTF8
+	}
+	return &GRPCDriver{addr: addr, ec: enc}
+}
+
+// GRPCDriver is a driver.Native that proxies Parse to a remote bblfsh
+// native driver over gRPC.
+type GRPCDriver struct {
+	addr string
+	ec   Encoding
+
+	mu     sync.Mutex
+	conn   *grpc.ClientConn
+	client grpcapi.DriverClient
+
+	logMu  sync.Mutex
+	logger *slog.Logger
+}
+
+func (d *GRPCDriver) setLogger(l *slog.Logger) {
+	d.logMu.Lock()
+	d.logger = l
+	d.logMu.Unlock()
+}
+
+func (d *GRPCDriver) currentLogger() *slog.Logger {
+	d.logMu.Lock()
+	defer d.logMu.Unlock()
+	if d.logger != nil {
+		return d.logger
+	}
+	return slog.Default()
+}
+
+// Start dials addr. The connection is established lazily by gRPC itself
+// on the first call, so Start mainly validates addr and wires up the
+// client stub.
+func (d *GRPCDriver) Start() error {
+	conn, err := grpc.Dial(d.addr, grpc.WithInsecure())
+	if err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	d.conn = conn
+	d.client = grpcapi.NewDriverClient(conn)
+	d.mu.Unlock()
+
+	d.currentLogger().Debug("grpc native driver started", "addr", d.addr, "encoding", d.ec)
+	return nil
+}
+
+// alive reports whether Start has established a client and Close hasn't
+// torn it down yet. It lets Pool (see livenessChecker) decide whether a
+// GRPCDriver worker needs replacing, the same way it does for *Driver.
+func (d *GRPCDriver) alive() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.client != nil
+}
+
+// Parse sends src to the remote driver and decodes its UAST response.
+func (d *GRPCDriver) Parse(ctx context.Context, src string) (nodes.Node, error) {
+	logger := LoggerFromContext(ctx)
+	d.setLogger(logger)
+
+	d.mu.Lock()
+	client := d.client
+	d.mu.Unlock()
+	if client == nil {
+		return nil, ErrNotRunning.New()
+	}
+
+	str, err := d.ec.Encode(src)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	logger.Debug("grpc native driver parse request", "blob_size", len(src), "encoding", d.ec)
+
+	resp, err := client.Parse(ctx, &grpcapi.ParseRequest{
+		Content:  str,
+		Encoding: string(d.ec),
+	})
+	if err != nil {
+		logger.Debug("grpc native driver parse response", "error", err, "elapsed", time.Since(start))
+		return nil, err
+	}
+
+	var ast nodes.Node
+	if len(resp.Ast) > 0 {
+		var raw interface{}
+		if err := json.Unmarshal(resp.Ast, &raw); err != nil {
+			return nil, err
+		}
+		if ast, err = nodes.ToNode(raw, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	st := status(strings.ToLower(resp.Status))
+	logger.Debug("grpc native driver parse response",
+		"status", st,
+		"errors_count", len(resp.Errors),
+		"elapsed", time.Since(start),
+	)
+
+	switch st {
+	case statusOK:
+		return ast, nil
+	case statusError:
+		return nil, driver.PartialParse(ast, resp.Errors)
+	case statusFatal:
+		return nil, driver.MultiError(resp.Errors)
+	default:
+		return nil, fmt.Errorf("unsupported status: %v", resp.Status)
+	}
+}
+
+// Close tears down the gRPC connection.
+func (d *GRPCDriver) Close() error {
+	d.mu.Lock()
+	conn := d.conn
+	d.conn = nil
+	d.client = nil
+	d.mu.Unlock()
+
+	d.currentLogger().Debug("grpc native driver closed")
+
+	if conn == nil {
+		return nil
+	}
+	return conn.Close()
+}
+
+var _ driver.Native = (*GRPCDriver)(nil)
+var _ livenessChecker = (*GRPCDriver)(nil)
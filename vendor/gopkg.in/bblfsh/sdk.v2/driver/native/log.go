@@ -0,0 +1,46 @@
+package native
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+)
+
+type loggerKeyT struct{}
+
+var loggerKey loggerKeyT
+
+// ContextWithLogger returns a copy of ctx carrying l. Driver picks it up in
+// Parse and keeps using it for its Start and Close records and its stderr
+// lines until the next Parse, so every log record produced while serving a
+// request carries the caller's structured attributes (e.g. a per-query
+// transaction id set by the Language UDF) instead of being anonymous.
+func ContextWithLogger(ctx context.Context, l *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey, l)
+}
+
+// LoggerFromContext returns the logger attached to ctx by ContextWithLogger,
+// or slog.Default() if none was attached.
+func LoggerFromContext(ctx context.Context) *slog.Logger {
+	if l, ok := ctx.Value(loggerKey).(*slog.Logger); ok && l != nil {
+		return l
+	}
+	return slog.Default()
+}
+
+// stderrWriter tees a driver's raw stderr into its most recently used
+// logger at debug level, one record per line, instead of dumping it to
+// os.Stderr where it can't be correlated with a request.
+type stderrWriter struct {
+	d *Driver
+}
+
+func (w *stderrWriter) Write(p []byte) (int, error) {
+	for _, line := range bytes.Split(bytes.TrimRight(p, "\n"), []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		w.d.currentLogger().Debug("native driver stderr", "line", string(line))
+	}
+	return len(p), nil
+}
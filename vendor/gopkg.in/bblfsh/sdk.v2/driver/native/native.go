@@ -5,10 +5,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"os"
 	"os/exec"
 	"strings"
 	"sync"
+	"time"
 
 	"context"
 
@@ -50,18 +52,52 @@ type Driver struct {
 	ec      Encoding
 	running bool
 
-	mu     sync.Mutex
-	enc    jsonlines.Encoder
-	dec    jsonlines.Decoder
-	stdin  io.Closer
-	stdout io.Closer
-	cmd    *exec.Cmd
+	mu      sync.Mutex
+	enc     jsonlines.Encoder
+	dec     jsonlines.Decoder
+	stdin   io.Closer
+	stdout  io.Closer
+	cmd     *exec.Cmd
+	exited  chan struct{}
+	waitErr error
+
+	logMu  sync.Mutex
+	logger *slog.Logger
+
+	policy   RestartPolicy
+	superMu  sync.Mutex
+	restarts []time.Time
+	lastErr  error
+	lastUsed time.Time
+}
+
+// setLogger records l as the logger to use for this driver's subsequent
+// stderr output, until the next Parse call replaces it.
+func (d *Driver) setLogger(l *slog.Logger) {
+	d.logMu.Lock()
+	d.logger = l
+	d.logMu.Unlock()
+}
+
+// currentLogger returns the logger set by the most recent Parse call, or
+// slog.Default() if none has run yet.
+func (d *Driver) currentLogger() *slog.Logger {
+	d.logMu.Lock()
+	defer d.logMu.Unlock()
+	if d.logger != nil {
+		return d.logger
+	}
+	return slog.Default()
 }
 
 // Start executes the given native driver and prepares it to parse code.
 func (d *Driver) Start() error {
+	if d.policy == (RestartPolicy{}) {
+		d.policy = DefaultRestartPolicy()
+	}
+
 	d.cmd = exec.Command(d.bin)
-	d.cmd.Stderr = os.Stderr
+	d.cmd.Stderr = &stderrWriter{d: d}
 
 	stdin, err := d.cmd.StdinPipe()
 	if err != nil {
@@ -81,7 +117,13 @@ func (d *Driver) Start() error {
 
 	err = d.cmd.Start()
 	if err == nil {
+		d.superMu.Lock()
 		d.running = true
+		d.lastUsed = time.Now()
+		d.superMu.Unlock()
+		d.exited = make(chan struct{})
+		go d.waitForExit(d.cmd, d.exited)
+		d.currentLogger().Debug("native driver started", "bin", d.bin, "encoding", d.ec)
 		return nil
 	}
 	stdin.Close()
@@ -89,6 +131,26 @@ func (d *Driver) Start() error {
 	return err
 }
 
+// waitForExit blocks until cmd exits, then marks the driver down so the
+// next Parse call knows to restart it rather than writing to a dead pipe.
+// It runs in its own goroutine so a crash is noticed even between Parse
+// calls, and owns the only call to cmd.Wait so Close can simply wait on
+// exited instead of calling it a second time.
+func (d *Driver) waitForExit(cmd *exec.Cmd, exited chan struct{}) {
+	err := cmd.Wait()
+
+	d.superMu.Lock()
+	d.running = false
+	d.waitErr = err
+	if err != nil {
+		d.lastErr = err
+	}
+	d.superMu.Unlock()
+
+	close(exited)
+	d.currentLogger().Debug("native driver exited", "error", err)
+}
+
 // parseRequest is the request used to communicate the driver with the
 // native driver via json.
 type parseRequest struct {
@@ -128,13 +190,39 @@ func (r *parseResponse) UnmarshalJSON(data []byte) error {
 
 // Parse sends a request to the native driver and returns its response.
 func (d *Driver) Parse(ctx context.Context, src string) (nodes.Node, error) {
-	if !d.running {
-		return nil, ErrNotRunning.New()
+	logger := LoggerFromContext(ctx)
+	d.setLogger(logger)
+
+	if err := d.ensureAlive(); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	logger.Debug("native driver parse request", "blob_size", len(src), "encoding", d.ec)
+
+	node, status, errs, err := d.parse(src)
+	d.superMu.Lock()
+	d.lastUsed = time.Now()
+	d.superMu.Unlock()
+
+	logger.Debug("native driver parse response",
+		"status", status,
+		"errors_count", len(errs),
+		"elapsed", time.Since(start),
+	)
+	return node, err
+}
+
+// parse is the unlogged core of Parse, returning the reported status
+// alongside the usual (node, error) so Parse can log them uniformly.
+func (d *Driver) parse(src string) (nodes.Node, status, []string, error) {
+	if !d.alive() {
+		return nil, "", nil, ErrNotRunning.New()
 	}
 
 	str, err := d.ec.Encode(src)
 	if err != nil {
-		return nil, err
+		return nil, "", nil, err
 	}
 
 	d.mu.Lock()
@@ -152,37 +240,67 @@ func (d *Driver) Parse(ctx context.Context, src string) (nodes.Node, error) {
 		// TODO: this reads a single line only; we can be smarter and read the whole log if driver cannot recover
 		if err := d.dec.Decode(&raw); err != nil {
 			// stream is broken on both sides, cannot get additional info
-			return nil, err
+			return nil, "", nil, err
 		}
-		return nil, fmt.Errorf("error: %v; %s", err, string(raw))
+		return nil, "", nil, fmt.Errorf("error: %v; %s", err, string(raw))
 	}
 
 	var r parseResponse
 	if err := d.dec.Decode(&r); err != nil {
-		return nil, err
+		return nil, "", nil, err
 	}
 	switch r.Status {
 	case statusOK:
-		return r.AST, nil
+		return r.AST, r.Status, r.Errors, nil
 	case statusError:
-		return nil, driver.PartialParse(r.AST, r.Errors)
+		return nil, r.Status, r.Errors, driver.PartialParse(r.AST, r.Errors)
 	case statusFatal:
-		return nil, driver.MultiError(r.Errors)
+		return nil, r.Status, r.Errors, driver.MultiError(r.Errors)
 	default:
-		return nil, fmt.Errorf("unsupported status: %v", r.Status)
+		return nil, r.Status, r.Errors, fmt.Errorf("unsupported status: %v", r.Status)
 	}
 }
 
+// alive reports whether the underlying subprocess is still running, i.e.
+// it has neither exited on its own nor been reaped by Close.
+func (d *Driver) alive() bool {
+	d.superMu.Lock()
+	defer d.superMu.Unlock()
+	return d.running
+}
+
 // Stop stops the execution of the native driver.
 func (d *Driver) Close() error {
+	defer func() { d.currentLogger().Debug("native driver closed") }()
+
+	if d.stdin == nil {
+		// Start never got far enough to launch a subprocess; nothing to
+		// tear down.
+		return nil
+	}
+
+	// A Parse call may still be blocked inside encode/decode, holding d.mu.
+	// Kill the subprocess first: that breaks its stdin/stdout pipes, which
+	// unblocks the in-flight encode/decode with an error and lets it
+	// release d.mu. Only then do we take d.mu ourselves, so closing the
+	// pipes below never races with parse's use of them.
+	if d.cmd.Process != nil {
+		_ = d.cmd.Process.Kill()
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
 	var last error
 	if err := d.stdin.Close(); err != nil {
 		last = err
 	}
-	err := d.cmd.Wait()
+	if d.exited != nil {
+		<-d.exited
+	}
 	err2 := d.stdout.Close()
-	if err != nil {
-		return err
+	if d.waitErr != nil {
+		return d.waitErr
 	}
 	if er, ok := err2.(*os.PathError); ok && er.Err == os.ErrClosed {
 		err2 = nil
@@ -0,0 +1,344 @@
+package native
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"gopkg.in/bblfsh/sdk.v2/driver"
+	"gopkg.in/bblfsh/sdk.v2/uast/nodes"
+)
+
+// PoolOptions configures the size and behavior of a Pool.
+type PoolOptions struct {
+	// Min is the number of workers started eagerly by Start and kept
+	// warm even when idle. Defaults to 1.
+	Min int
+	// Max is the upper bound on concurrently running workers. Parse
+	// calls beyond Max block until a worker becomes idle. Defaults to
+	// Min.
+	Max int
+	// Timeout bounds how long a single Parse call may run before its
+	// worker is considered wedged, killed and replaced. Zero disables
+	// the timeout.
+	Timeout time.Duration
+}
+
+func (o PoolOptions) withDefaults() PoolOptions {
+	if o.Min <= 0 {
+		o.Min = 1
+	}
+	if o.Max < o.Min {
+		o.Max = o.Min
+	}
+	return o
+}
+
+// PoolStats is a snapshot of a Pool's worker accounting, meant to be
+// exported as metrics by callers.
+type PoolStats struct {
+	// InUse is the number of workers currently serving a Parse call.
+	InUse int
+	// Idle is the number of warm, idle workers.
+	Idle int
+	// Queued is the number of Parse calls waiting for a worker.
+	Queued int
+	// Restarts is the total number of worker crashes/timeouts recovered
+	// by the pool since it was created.
+	Restarts int
+}
+
+// NewPool creates a Pool dispatching Parse calls to whichever worker
+// produced by newWorker is idle, growing and shrinking the number of live
+// workers between opt.Min and opt.Max. newWorker is called to both create
+// and Start a worker; it is always called with the pool's lock held, so
+// calls never overlap. Pool itself satisfies driver.Native, so it can
+// be used anywhere a single worker is used today.
+//
+// Use NewExecPool or NewGRPCPool for the two worker kinds native supports;
+// NewPool is exported for callers that need a custom newWorker.
+func NewPool(newWorker func() (driver.Native, error), opt PoolOptions) *Pool {
+	return &Pool{newWorker: newWorker, opt: opt.withDefaults()}
+}
+
+// NewExecPool creates a Pool of native driver subprocesses of bin,
+// communicating over stdin/stdout, as a drop-in replacement for a single
+// *Driver.
+func NewExecPool(bin string, enc Encoding, opt PoolOptions) *Pool {
+	return NewPool(func() (driver.Native, error) {
+		d := NewDriverAt(bin, enc)
+		if err := d.Start(); err != nil {
+			return nil, err
+		}
+		return d, nil
+	}, opt)
+}
+
+// NewGRPCPool creates a Pool of GRPCDriver connections to addr, as a
+// drop-in replacement for a single *GRPCDriver.
+func NewGRPCPool(addr string, enc Encoding, opt PoolOptions) *Pool {
+	return NewPool(func() (driver.Native, error) {
+		d := NewGRPCDriver(addr, enc)
+		if err := d.Start(); err != nil {
+			return nil, err
+		}
+		return d, nil
+	}, opt)
+}
+
+// Pool dispatches Parse calls across several driver.Native workers,
+// parallelizing what would otherwise be a single worker's serialized
+// Parse calls. Workers are produced by newWorker, so the same Pool
+// implementation works over exec-based or gRPC-based drivers alike.
+type Pool struct {
+	newWorker func() (driver.Native, error)
+	opt       PoolOptions
+
+	mu       sync.Mutex
+	closed   bool
+	live     int
+	idle     []driver.Native
+	waiters  []chan driver.Native
+	restarts int
+}
+
+// Start launches opt.Min workers. Further workers, up to opt.Max, are
+// started lazily as Parse calls need them.
+func (p *Pool) Start() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for i := 0; i < p.opt.Min; i++ {
+		d, err := p.spawnLocked()
+		if err != nil {
+			return err
+		}
+		p.idle = append(p.idle, d)
+	}
+	return nil
+}
+
+// spawnLocked starts a new worker. Callers must hold p.mu.
+func (p *Pool) spawnLocked() (driver.Native, error) {
+	d, err := p.newWorker()
+	if err != nil {
+		return nil, err
+	}
+	p.live++
+	return d, nil
+}
+
+// acquire returns an idle worker, spawning one if under Max, or blocks
+// until one is released or ctx is done.
+func (p *Pool) acquire(ctx context.Context) (driver.Native, error) {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil, ErrNotRunning.New()
+	}
+
+	if n := len(p.idle); n > 0 {
+		d := p.idle[n-1]
+		p.idle = p.idle[:n-1]
+		p.mu.Unlock()
+		return d, nil
+	}
+
+	if p.live < p.opt.Max {
+		d, err := p.spawnLocked()
+		p.mu.Unlock()
+		return d, err
+	}
+
+	wait := make(chan driver.Native, 1)
+	p.waiters = append(p.waiters, wait)
+	p.mu.Unlock()
+
+	select {
+	case d := <-wait:
+		if d == nil {
+			return nil, ErrNotRunning.New()
+		}
+		return d, nil
+	case <-ctx.Done():
+		// We may be racing release/replenishLocked, which pop p.waiters
+		// and hand a worker to wait under p.mu. Re-lock and deregister
+		// ourselves; if we're no longer in p.waiters, a worker was
+		// already delivered into wait, so drain and release it back
+		// instead of leaking it.
+		p.mu.Lock()
+		removed := false
+		for i, w := range p.waiters {
+			if w == wait {
+				p.waiters = append(p.waiters[:i], p.waiters[i+1:]...)
+				removed = true
+				break
+			}
+		}
+		p.mu.Unlock()
+
+		if !removed {
+			if d := <-wait; d != nil {
+				p.release(d, false)
+			}
+		}
+		return nil, ctx.Err()
+	}
+}
+
+// release returns a worker to the idle set, handing it directly to a
+// waiter if one is queued. If broken is true the worker is torn down and
+// replaced instead of reused. If the pool holds more idle workers than
+// opt.Min, the excess worker is torn down rather than kept warm.
+func (p *Pool) release(d driver.Native, broken bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if broken {
+		p.restarts++
+		p.live--
+		go d.Close()
+		if !p.closed {
+			p.replenishLocked()
+		}
+		return
+	}
+
+	if p.closed {
+		go d.Close()
+		return
+	}
+
+	if n := len(p.waiters); n > 0 {
+		wait := p.waiters[0]
+		p.waiters = p.waiters[1:]
+		wait <- d
+		return
+	}
+
+	if len(p.idle) >= p.opt.Min && p.live > p.opt.Min {
+		p.live--
+		go d.Close()
+		return
+	}
+
+	p.idle = append(p.idle, d)
+}
+
+// replenishLocked tops the pool back up to Min workers after one was torn
+// down due to a crash, handing the new worker to a waiter if one is
+// queued. Callers must hold p.mu.
+func (p *Pool) replenishLocked() {
+	if p.live >= p.opt.Min && len(p.waiters) == 0 {
+		return
+	}
+
+	d, err := p.spawnLocked()
+	if err != nil {
+		// Leave the pool under Min; the next acquire will retry.
+		return
+	}
+
+	if n := len(p.waiters); n > 0 {
+		wait := p.waiters[0]
+		p.waiters = p.waiters[1:]
+		wait <- d
+		return
+	}
+
+	p.idle = append(p.idle, d)
+}
+
+// Parse dispatches src to an idle worker, respecting ctx cancellation both
+// while waiting for a worker and while the worker is parsing. A worker that
+// is still parsing when ctx expires, or that reports itself no longer alive
+// after the call (see livenessChecker), is considered crashed: it is killed
+// and replaced rather than reused. An error returned by a worker that is
+// still alive does not cause it to be replaced.
+func (p *Pool) Parse(ctx context.Context, src string) (nodes.Node, error) {
+	if p.opt.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.opt.Timeout)
+		defer cancel()
+	}
+
+	d, err := p.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	type result struct {
+		n   nodes.Node
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		n, err := d.Parse(ctx, src)
+		done <- result{n, err}
+	}()
+
+	select {
+	case r := <-done:
+		p.release(d, !isAlive(d))
+		return r.n, r.err
+	case <-ctx.Done():
+		// The worker may still be blocked reading/writing on the pipe;
+		// killing it unblocks the goroutine above and frees the slot.
+		p.release(d, true)
+		return nil, ctx.Err()
+	}
+}
+
+// livenessChecker is implemented by worker kinds that can report their own
+// health (*Driver, *GRPCDriver). Workers that don't implement it are
+// assumed alive, since the pool has no other way to tell.
+type livenessChecker interface {
+	alive() bool
+}
+
+func isAlive(d driver.Native) bool {
+	lc, ok := d.(livenessChecker)
+	if !ok {
+		return true
+	}
+	return lc.alive()
+}
+
+// Stats returns a snapshot of the pool's worker accounting.
+func (p *Pool) Stats() PoolStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return PoolStats{
+		InUse:    p.live - len(p.idle),
+		Idle:     len(p.idle),
+		Queued:   len(p.waiters),
+		Restarts: p.restarts,
+	}
+}
+
+// Close stops every worker in the pool. Parse calls already in flight are
+// allowed to finish; queued waiters are released with ErrNotRunning.
+func (p *Pool) Close() error {
+	p.mu.Lock()
+	p.closed = true
+	idle := p.idle
+	p.idle = nil
+	waiters := p.waiters
+	p.waiters = nil
+	p.mu.Unlock()
+
+	for _, wait := range waiters {
+		close(wait)
+	}
+
+	var last error
+	for _, d := range idle {
+		if err := d.Close(); err != nil {
+			last = err
+		}
+	}
+	return last
+}
+
+var _ driver.Native = (*Pool)(nil)
@@ -0,0 +1,157 @@
+package native
+
+import (
+	"fmt"
+	"time"
+)
+
+// RestartPolicy bounds how aggressively Driver restarts a crashed or
+// wedged subprocess.
+type RestartPolicy struct {
+	// MaxRestarts is the number of restarts allowed within Window before
+	// Driver gives up and returns an error instead of relaunching again.
+	MaxRestarts int
+	// Window is the sliding time window over which MaxRestarts applies.
+	Window time.Duration
+	// BaseDelay is the backoff before the first restart; it doubles on
+	// each consecutive restart within Window, capped at MaxDelay.
+	BaseDelay time.Duration
+	// MaxDelay caps the exponential backoff between restarts.
+	MaxDelay time.Duration
+	// ProbeAfterIdle is how long the driver may sit unused before the
+	// next Parse call is preceded by a liveness probe.
+	ProbeAfterIdle time.Duration
+	// ProbeTimeout bounds how long the liveness probe waits for a reply
+	// before the subprocess is considered wedged.
+	ProbeTimeout time.Duration
+}
+
+// DefaultRestartPolicy returns the RestartPolicy used by Driver when none
+// is set explicitly.
+func DefaultRestartPolicy() RestartPolicy {
+	return RestartPolicy{
+		MaxRestarts:    5,
+		Window:         time.Minute,
+		BaseDelay:      100 * time.Millisecond,
+		MaxDelay:       5 * time.Second,
+		ProbeAfterIdle: 30 * time.Second,
+		ProbeTimeout:   2 * time.Second,
+	}
+}
+
+// DriverStats reports a Driver's restart history, for callers that want to
+// export it as a metric or surface it in diagnostics.
+type DriverStats struct {
+	Restarts int
+	LastErr  error
+}
+
+// Stats returns a snapshot of the driver's restart accounting.
+func (d *Driver) Stats() DriverStats {
+	d.superMu.Lock()
+	defer d.superMu.Unlock()
+	return DriverStats{
+		Restarts: len(d.restarts),
+		LastErr:  d.lastErr,
+	}
+}
+
+// ensureAlive makes sure the subprocess is both running and responsive
+// before a Parse call is dispatched to it: it relaunches a crashed
+// process, and probes one that has been idle for a while in case it is
+// wedged rather than dead.
+func (d *Driver) ensureAlive() error {
+	if !d.alive() {
+		return d.restart()
+	}
+
+	d.superMu.Lock()
+	idle := time.Since(d.lastUsed)
+	d.superMu.Unlock()
+
+	if idle < d.policy.ProbeAfterIdle {
+		return nil
+	}
+
+	if err := d.probe(); err != nil {
+		d.currentLogger().Debug("native driver failed liveness probe", "error", err)
+		return d.restart()
+	}
+	return nil
+}
+
+// probe sends a trivial, well-known parse request and fails if the
+// subprocess doesn't answer within d.policy.ProbeTimeout, which is how a
+// wedged (as opposed to dead) subprocess is detected.
+func (d *Driver) probe() error {
+	type reply struct {
+		status status
+		err    error
+	}
+	done := make(chan reply, 1)
+	go func() {
+		_, st, _, err := d.parse("")
+		done <- reply{st, err}
+	}()
+
+	select {
+	case r := <-done:
+		// A known status reported by the subprocess itself - even "error"
+		// or "fatal" for the trivial empty input - proves it is alive and
+		// answering; a transport-level err (io/decode failures,
+		// ErrNotRunning) or an unrecognised status fails the probe.
+		if r.err != nil && r.status != statusError && r.status != statusFatal {
+			return r.err
+		}
+		return nil
+	case <-time.After(d.policy.ProbeTimeout):
+		return fmt.Errorf("native driver: liveness probe timed out after %s", d.policy.ProbeTimeout)
+	}
+}
+
+// restart tears down the current subprocess, if any, and launches a fresh
+// one, honoring the exponential backoff and restart budget in d.policy.
+func (d *Driver) restart() error {
+	d.superMu.Lock()
+	now := time.Now()
+	cutoff := now.Add(-d.policy.Window)
+	kept := d.restarts[:0]
+	for _, t := range d.restarts {
+		if t.After(cutoff) {
+			kept = append(kept, t)
+		}
+	}
+	d.restarts = kept
+
+	if len(d.restarts) >= d.policy.MaxRestarts {
+		err := fmt.Errorf("native driver: exceeded %d restarts within %s", d.policy.MaxRestarts, d.policy.Window)
+		d.lastErr = err
+		d.superMu.Unlock()
+		return err
+	}
+
+	delay := d.policy.BaseDelay << uint(len(d.restarts))
+	if delay > d.policy.MaxDelay || delay <= 0 {
+		delay = d.policy.MaxDelay
+	}
+	d.restarts = append(d.restarts, now)
+	d.superMu.Unlock()
+
+	if delay > 0 {
+		time.Sleep(delay)
+	}
+
+	d.currentLogger().Debug("native driver restarting", "delay", delay)
+
+	// Best-effort teardown: the old process may already be dead, or its
+	// pipes already broken, either of which Close tolerates.
+	_ = d.Close()
+
+	if err := d.Start(); err != nil {
+		d.superMu.Lock()
+		d.lastErr = err
+		d.superMu.Unlock()
+		return err
+	}
+	return nil
+}